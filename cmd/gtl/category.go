@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// category returns the project a span's title belongs to, by splitting
+// it on the first ": " the way gtimelog groups "Project: subtask
+// detail" entries. Titles without that separator fall into a catch-all
+// bucket.
+func category(title string) string {
+	if project, _, found := strings.Cut(title, ": "); found {
+		return project
+	}
+	return "Uncategorized"
+}
+
+// Categories totals worked time per category across the week, reusing
+// DayReport.Worked's starred-non-work-span rule.
+func (w *WeekReport) Categories() map[string]time.Duration {
+	totals := make(map[string]time.Duration)
+	for _, day := range w.Days {
+		for _, iv := range day.Intervals() {
+			if iv.Work {
+				totals[category(iv.Title)] += iv.End.Sub(iv.Start)
+			}
+		}
+	}
+	return totals
+}
+
+// PrintCategories prints the per-category breakdown for the week,
+// sorted by name, with each category's share of the week's worked time.
+func (w *WeekReport) PrintCategories() {
+	if len(w.Days) < 1 {
+		return
+	}
+
+	totals := w.Categories()
+	var worked time.Duration
+	names := make([]string, 0, len(totals))
+	for name, d := range totals {
+		names = append(names, name)
+		worked += d
+	}
+	sort.Strings(names)
+
+	fmt.Printf("%04d week %02d:\n", w.Year, w.Week)
+	for _, name := range names {
+		d := totals[name]
+		var pct float64
+		if worked > 0 {
+			pct = 100 * float64(d) / float64(worked)
+		}
+		fmt.Printf("  %-20s %8s (%4.1f%%)\n", name, d, pct)
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import "time"
+
+// clockOverride pins now's return value when --clock is given, so
+// reports (and writes) are reproducible in tests instead of depending
+// on the wall clock.
+var clockOverride time.Time
+
+func setClock(t time.Time) {
+	clockOverride = t
+}
+
+func now() time.Time {
+	if !clockOverride.IsZero() {
+		return clockOverride
+	}
+	return time.Now()
+}
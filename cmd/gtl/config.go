@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the reporting knobs that gtimelogrc can override: the
+// length of an expected workday, how far past midnight an entry still
+// belongs to the previous day, which weekday a week starts on, and
+// holidays to exclude from the expected-hours calculation.
+type Config struct {
+	HoursPerDay     time.Duration
+	VirtualMidnight time.Duration
+	WeekStart       time.Weekday
+	Holidays        map[string]bool
+}
+
+// DefaultConfig matches gtimelog's own defaults: a 7-hour day, a
+// virtual midnight of 2am, and weeks starting on Monday.
+func DefaultConfig() Config {
+	return Config{
+		HoursPerDay:     7 * time.Hour,
+		VirtualMidnight: 2 * time.Hour,
+		WeekStart:       time.Monday,
+		Holidays:        map[string]bool{},
+	}
+}
+
+// loadConfig reads gtimelogrc-style "key = value" settings from path. A
+// missing file isn't an error; it just yields DefaultConfig().
+func loadConfig(path string) (Config, error) {
+	cfg := DefaultConfig()
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "[") {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "hours_per_day":
+			hours, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return cfg, fmt.Errorf("%s: hours_per_day: %w", path, err)
+			}
+			cfg.HoursPerDay = time.Duration(hours * float64(time.Hour))
+		case "virtual_midnight":
+			t, err := time.Parse("15:04", value)
+			if err != nil {
+				return cfg, fmt.Errorf("%s: virtual_midnight: %w", path, err)
+			}
+			cfg.VirtualMidnight = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+		case "week_start":
+			switch strings.ToLower(value) {
+			case "monday":
+				cfg.WeekStart = time.Monday
+			case "sunday":
+				cfg.WeekStart = time.Sunday
+			default:
+				return cfg, fmt.Errorf("%s: week_start: must be Monday or Sunday, got %q", path, value)
+			}
+		case "holidays":
+			for _, d := range strings.Split(value, ",") {
+				if d = strings.TrimSpace(d); d != "" {
+					cfg.Holidays[d] = true
+				}
+			}
+		}
+	}
+	return cfg, scanner.Err()
+}
+
+// IsHoliday reports whether dt falls on a configured holiday.
+func (c Config) IsHoliday(dt time.Time) bool {
+	return c.Holidays[dt.Format("2006-01-02")]
+}
+
+// EffectiveDate shifts dt back by VirtualMidnight, so entries made
+// shortly after midnight still group with the previous day's work.
+func (c Config) EffectiveDate(dt time.Time) time.Time {
+	return dt.Add(-c.VirtualMidnight)
+}
+
+// WeekNumber returns the ISO year/week dt's effective date falls in,
+// honoring WeekStart for weeks that begin on Sunday instead of Monday.
+func (c Config) WeekNumber(dt time.Time) (int, int) {
+	dt = c.EffectiveDate(dt)
+	if c.WeekStart == time.Sunday {
+		dt = dt.AddDate(0, 0, 1)
+	}
+	return dt.ISOWeek()
+}
+
+// isWeekend reports whether dt falls on a Saturday or Sunday.
+func isWeekend(dt time.Time) bool {
+	d := dt.Weekday()
+	return d == time.Saturday || d == time.Sunday
+}
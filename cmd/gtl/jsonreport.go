@@ -0,0 +1,54 @@
+package main
+
+// jsonReport is the --json report output: every parsed week, with each
+// day's spans and the week's category breakdown.
+type jsonReport struct {
+	Weeks []jsonWeek `json:"weeks"`
+}
+
+type jsonWeek struct {
+	Year       int                `json:"year"`
+	Week       int                `json:"week"`
+	Days       []jsonDay          `json:"days"`
+	Categories map[string]float64 `json:"categories"`
+}
+
+type jsonDay struct {
+	Date  string     `json:"date"`
+	Spans []jsonSpan `json:"spans"`
+}
+
+type jsonSpan struct {
+	Start    string  `json:"start"`
+	End      string  `json:"end"`
+	Hours    float64 `json:"hours"`
+	Work     bool    `json:"work"`
+	Title    string  `json:"title"`
+	Category string  `json:"category"`
+}
+
+func toJSONReport(weeks []WeekReport) jsonReport {
+	out := jsonReport{Weeks: make([]jsonWeek, 0, len(weeks))}
+	for _, w := range weeks {
+		jw := jsonWeek{Year: w.Year, Week: w.Week, Categories: make(map[string]float64)}
+		for name, d := range w.Categories() {
+			jw.Categories[name] = d.Hours()
+		}
+		for _, day := range w.Days {
+			jd := jsonDay{Date: day.Date.Format("2006-01-02")}
+			for _, iv := range day.Intervals() {
+				jd.Spans = append(jd.Spans, jsonSpan{
+					Start:    iv.Start.Format(timeFormat),
+					End:      iv.End.Format(timeFormat),
+					Hours:    iv.End.Sub(iv.Start).Hours(),
+					Work:     iv.Work,
+					Title:    iv.Title,
+					Category: category(iv.Title),
+				})
+			}
+			jw.Days = append(jw.Days, jd)
+		}
+		out.Weeks = append(out.Weeks, jw)
+	}
+	return out
+}
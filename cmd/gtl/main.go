@@ -1,18 +1,23 @@
 package main
 
 import (
-	"bufio"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"os/user"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"time"
 )
 
-const timelog = ".gtimelog/timelog.txt"
+const (
+	timelog     = ".gtimelog/timelog.txt"
+	worklogRC   = ".gtimelog/config"
+	gtimelogrc  = ".gtimelog/gtimelogrc"
+	pendingFile = ".gtimelog/pending"
+)
 
 func main() {
 	usr, err := user.Current()
@@ -20,166 +25,342 @@ func main() {
 		log.Fatal(err.Error())
 	}
 
-	timelog := filepath.Join(usr.HomeDir, timelog)
-
-	f, err := os.Open(timelog)
+	path := filepath.Join(usr.HomeDir, timelog)
+	pendingPath := filepath.Join(usr.HomeDir, pendingFile)
 
+	cfg, err := loadConfig(filepath.Join(usr.HomeDir, gtimelogrc))
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 
-	/*
-		Here is a formal grammar:
+	fs := flag.NewFlagSet("gtl", flag.ExitOnError)
+	clock := fs.String("clock", "", "pin \"now\" to this timestamp (YYYY-MM-DD HH:MM), for reproducible reports")
+	fs.Parse(os.Args[1:])
 
-		file ::= (entry|day-separator|comment|old-style-comment)*
+	if *clock != "" {
+		t, err := time.Parse(timeFormat, *clock)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		setClock(t)
+	}
 
-		entry ::= timestamp ":" SPACE title NEWLINE
+	args := fs.Args()
+	cmd := "report"
+	if len(args) > 0 {
+		cmd = args[0]
+		args = args[1:]
+	}
 
-		day-separator ::= NEWLINE
+	switch cmd {
+	case "report":
+		report(path, cfg, args)
+	case "start":
+		if len(args) < 1 {
+			log.Fatal("usage: timelog start <title>")
+		}
+		start(path, pendingPath, strings.Join(args, " "))
+	case "stop":
+		stop(path, pendingPath, strings.Join(args, " "))
+	case "track":
+		if len(args) < 2 {
+			log.Fatal("usage: timelog track <duration> <title>")
+		}
+		track(path, args[0], strings.Join(args[1:], " "))
+	case "export":
+		export(path, cfg, filepath.Join(usr.HomeDir, worklogRC), args)
+	default:
+		log.Fatalf("unknown command %q", cmd)
+	}
+}
 
-		comment ::= "#" anything* NEWLINE
+// report scans the whole timelog and prints a per-week summary, as the
+// tool has always done. --categories prints only the per-category
+// breakdown for each week, --json emits the full parsed report instead
+// of printing anything, --watch turns it into a live dashboard that
+// refreshes as entries are appended to path, --month reports a single
+// calendar month, and --from/--to report an arbitrary date range.
+func report(path string, cfg Config, args []string) {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	categories := fs.Bool("categories", false, "show only the per-category breakdown")
+	jsonOut := fs.Bool("json", false, "emit the full report as JSON")
+	watchFlag := fs.Bool("watch", false, "watch path and refresh the report as entries are appended")
+	month := fs.String("month", "", "report a single calendar month, e.g. 2024-03")
+	from := fs.String("from", "", "start of an arbitrary date range, e.g. 2024-03-01")
+	to := fs.String("to", "", "end of an arbitrary date range, e.g. 2024-03-15")
+	fs.Parse(args)
+
+	if *watchFlag {
+		if err := watch(path, cfg); err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
 
-		old-style-comment ::= anything* NEWLINE
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	defer f.Close()
+
+	switch {
+	case *month != "":
+		reportMonth(f, cfg, *month)
+	case *from != "" || *to != "":
+		reportRange(f, cfg, *from, *to)
+	default:
+		reportWeeks(f, cfg, *categories, *jsonOut)
+	}
+}
 
-		title ::= anything*
-		timestamp is YYYY-MM-DD HH:MM with a single space between the date and the time.
+// reportWeeks prints (or, with jsonOut, emits as JSON) the default
+// per-week summary for every week found in f.
+func reportWeeks(f *os.File, cfg Config, categories, jsonOut bool) {
+	var weeks []WeekReport
+
+	err := scanReports(
+		func(fn func(Entry) error) error { return scanEntries(f, fn) },
+		func(dt time.Time) Reporter {
+			year, week := cfg.WeekNumber(dt)
+			wr := &WeekReport{}
+			wr.New(year, week, dt, cfg)
+			return wr
+		},
+		func(r Reporter) {
+			wr := r.(*WeekReport)
+			switch {
+			case jsonOut:
+				weeks = append(weeks, *wr)
+			case categories:
+				wr.PrintCategories()
+			default:
+				wr.Print()
+			}
+		},
+	)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
 
-		anything is any character except a newline.
+	if jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(toJSONReport(weeks)); err != nil {
+			log.Fatal(err.Error())
+		}
+	}
+}
 
-		NEWLINE is whatever Python considers it to be (i.e. CR LF or just LF).
+// reportMonth prints the single calendar month named by spec (e.g.
+// "2024-03"), ignoring every other month found in f.
+func reportMonth(f *os.File, cfg Config, spec string) {
+	target, err := time.Parse("2006-01", spec)
+	if err != nil {
+		log.Fatal(fmt.Errorf("--month: %w", err).Error())
+	}
 
-		GTimeLog adds a blank line between days. It ignores them when loading, but this is likely to change in the future.
+	err = scanReports(
+		func(fn func(Entry) error) error { return scanEntries(f, fn) },
+		func(dt time.Time) Reporter {
+			mr := &MonthReport{}
+			mr.New(dt, cfg)
+			return mr
+		},
+		func(r Reporter) {
+			mr := r.(*MonthReport)
+			if mr.Year == target.Year() && mr.Month == target.Month() {
+				mr.Print()
+			}
+		},
+	)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+}
 
-		GTimeLog considers any lines not starting with a valid timestamp to be comments. This is likely to change in the future, so please use '#' to indicate real comments if you find you need them.
+// reportRange prints a single RangeReport covering [from, to] (each
+// "YYYY-MM-DD"). --to left empty defaults to today, as told by now (so
+// --clock makes an open-ended range report reproducible), giving a
+// report of the range up to and including the still-in-progress day.
+// --from left empty defaults to the earliest date actually present in
+// f, since RangeReport.Print walks every calendar day between Start and
+// End and there's no other sensible start for an open lower bound.
+func reportRange(f *os.File, cfg Config, from, to string) {
+	lower := time.Time{}
+	if from != "" {
+		t, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			log.Fatal(fmt.Errorf("--from: %w", err).Error())
+		}
+		lower = t
+	}
 
-		All lines should be sorted by time. Currently GTimeLog won't complain if they're not, and it will sort them to compensate.
-	*/
-	// YYYY-MM-DD HH:MM: TITLE
-	rx := regexp.MustCompile(`(\d\d\d\d-\d\d-\d\d \d\d:\d\d): (.*)`)
+	upper := dateOnly(now())
+	if to != "" {
+		t, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			log.Fatal(fmt.Errorf("--to: %w", err).Error())
+		}
+		upper = t
+	}
 
-	// Time format
-	tf := "2006-01-02 15:04"
+	rr := newRangeReport(lower, upper, cfg)
+	var firstEff time.Time
+	seen := false
 
-	var current WeekReport
+	err := scanEntries(f, func(e Entry) error {
+		if rr.Boundary(e.When) {
+			return nil
+		}
+		if !seen {
+			firstEff = cfg.EffectiveDate(e.When)
+			seen = true
+		}
+		rr.Append(e.When, e.Title)
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err.Error())
+	}
 
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		line := scanner.Text()
-		match := rx.FindStringSubmatch(line)
-		if len(match) == 0 {
-			continue
+	// An explicit [from, to] with zero matching entries is still
+	// printed, to show a range with nothing logged; but an open --from
+	// has no sensible start to default to without any data to take it
+	// from.
+	if from == "" {
+		if !seen {
+			return
 		}
+		rr.Start = dateOnly(firstEff)
+	}
+	rr.Print()
+}
 
-		title := match[2]
-		dt, err := time.Parse(tf, match[1])
+// start closes out whatever task the last start left open, crediting it
+// up to now exactly as stop would, then leaves title open in its place:
+// the file's titles always describe the span ending at their own
+// timestamp, so title itself isn't known to be done until the next
+// start or stop closes it out in turn.
+func start(path, pendingPath, title string) {
+	prev, err := readPending(pendingPath)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
 
-		if err != nil {
-			log.Fatal(err.Error())
-		}
+	flushTitle := "arrived**"
+	if prev != "" {
+		flushTitle = prev
+	}
+	if err := appendEntry(path, now(), flushTitle); err != nil {
+		log.Fatal(err.Error())
+	}
 
-		year, week := dt.ISOWeek()
+	if err := writePending(pendingPath, title); err != nil {
+		log.Fatal(err.Error())
+	}
+}
 
-		//fmt.Println(line, "=>", dt, title, year, week)
+// stop closes out the current span: the task the last start left open,
+// if any, otherwise a bare "out**". A title may be given to explain
+// what's being stopped instead, overriding the open task and always
+// treated as non-work.
+func stop(path, pendingPath, title string) {
+	prev, err := readPending(pendingPath)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
 
-		if year == current.Year && week == current.Week {
-			current.Append(dt, title)
-		} else {
-			current.Print()
-			current.New(year, week, dt)
+	flushTitle := "out**"
+	if prev != "" {
+		flushTitle = prev
+	}
+	if title != "" {
+		if !isStarred(title) {
+			title += "**"
 		}
+		flushTitle = title
 	}
 
-	current.Print()
-}
-
-type WeekReport struct {
-	Year int
-	Week int
-	Days []*DayReport
-}
+	if err := appendEntry(path, now(), flushTitle); err != nil {
+		log.Fatal(err.Error())
+	}
 
-func (w *WeekReport) New(year, week int, start time.Time) {
-	w.Year = year
-	w.Week = week
-	w.Days = []*DayReport{NewDayReport(start)}
+	if err := clearPending(pendingPath); err != nil {
+		log.Fatal(err.Error())
+	}
 }
 
-func (w *WeekReport) Append(dt time.Time, title string) {
-	work := !isStarred(title)
-	day := w.lastDay()
+// track records a span of the given duration ending now, for work that
+// wasn't tracked live.
+func track(path, duration, title string) {
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
 
-	if day.Day == dt.Weekday() {
-		day.Spans = append(day.Spans, Span{dt, work})
-	} else {
-		w.Days = append(w.Days, NewDayReport(dt))
+	end := now()
+	last, err := lastEntry(path)
+	if err != nil {
+		log.Fatal(err.Error())
 	}
-}
 
-func (w *WeekReport) lastDay() *DayReport {
-	days := w.Days
-	return days[len(days)-1]
-}
+	spanStart := end.Add(-d)
+	if !last.When.IsZero() && spanStart.Before(last.When) {
+		log.Fatal(fmt.Errorf("track: %s before last entry %s", spanStart.Format(timeFormat), last.When.Format(timeFormat)).Error())
+	}
 
-func (w *WeekReport) Print() {
-	if len(w.Days) < 1 {
-		return
+	if last.When.IsZero() || !sameDay(last.When, spanStart) {
+		// No entry to anchor the start of a new day to, so record an
+		// arrival before appending the title that closes the span.
+		if err := appendEntry(path, spanStart, "arrived**"); err != nil {
+			log.Fatal(err.Error())
+		}
 	}
-	fmt.Printf("%04d week %02d:\n", w.Year, w.Week)
-	var days int
-	var worked time.Duration
-	for _, day := range w.Days {
-		day.Print()
-		days++
-		worked += day.Worked()
-	}
-	expected := 7 * time.Hour * time.Duration(days)
-	overtime := worked - expected
-	daily := worked / time.Duration(days)
-	fmt.Printf("   daily: %s\n", daily)
-	fmt.Printf("  worked: %s\n", worked)
-	fmt.Printf("  expect: %s\n", expected)
-	if overtime > 0 {
-		fmt.Printf("    over: %s\n", overtime)
-	} else {
-		fmt.Printf("   under: %s\n", -overtime)
+
+	if err := appendEntry(path, end, title); err != nil {
+		log.Fatal(err.Error())
 	}
 }
 
-type DayReport struct {
-	Day   time.Weekday
-	Start time.Time
-	Spans []Span
-}
+// export scans the timelog and pushes worked spans that carry a ticket
+// key to Jira/Tempo as worklog entries.
+func export(path string, cfg Config, configPath string, args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "print worklog payloads instead of posting them")
+	fs.Parse(args)
 
-func NewDayReport(start time.Time) *DayReport {
-	return &DayReport{
-		Day:   start.Weekday(),
-		Start: start,
+	wcfg, err := loadWorklogConfig(configPath)
+	if err != nil {
+		log.Fatal(err.Error())
 	}
-}
+	client := NewWorklogClient(wcfg, *dryRun)
 
-func (d *DayReport) Print() {
-	fmt.Printf("  %s: %s\n", d.Start.Format("2006-01-02"), d.Worked())
-}
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	defer f.Close()
+
+	var current WeekReport
+
+	err = scanEntries(f, func(e Entry) error {
+		year, week := cfg.WeekNumber(e.When)
 
-func (d *DayReport) Worked() time.Duration {
-	var worked time.Duration
-	start := d.Start
-	for _, span := range d.Spans {
-		if span.Work {
-			worked += span.End.Sub(start)
+		if year == current.Year && week == current.Week {
+			current.Append(e.When, e.Title)
+		} else {
+			if err := current.ExportWorklogs(client); err != nil {
+				return err
+			}
+			current.New(year, week, e.When, cfg)
 		}
-		start = span.End
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err.Error())
 	}
-	return worked
-}
-
-type Span struct {
-	End  time.Time
-	Work bool
-}
 
-func isStarred(title string) bool {
-	_, found := strings.CutSuffix(title, "**")
-	return found
+	if err := current.ExportWorklogs(client); err != nil {
+		log.Fatal(err.Error())
+	}
 }
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// MonthReport aggregates entries into a single calendar month, the way
+// WeekReport aggregates a single ISO week.
+type MonthReport struct {
+	Year   int
+	Month  time.Month
+	Days   []*DayReport
+	Config Config
+}
+
+// New starts a MonthReport for the calendar month start's effective
+// date falls in.
+func (m *MonthReport) New(start time.Time, config Config) {
+	eff := config.EffectiveDate(start)
+	m.Year = eff.Year()
+	m.Month = eff.Month()
+	m.Config = config
+	m.Days = []*DayReport{NewDayReport(start, config)}
+}
+
+func (m *MonthReport) Append(dt time.Time, title string) {
+	m.Days = appendToDays(m.Days, m.Config, dt, title)
+}
+
+// Boundary reports whether dt falls in a different calendar month than
+// the one m is currently aggregating.
+func (m *MonthReport) Boundary(dt time.Time) bool {
+	eff := m.Config.EffectiveDate(dt)
+	return eff.Year() != m.Year || eff.Month() != m.Month
+}
+
+// Print prints m's total worked/expected/overtime, the same shape as
+// WeekReport.Print but for a calendar month.
+func (m *MonthReport) Print() {
+	if len(m.Days) < 1 {
+		return
+	}
+	fmt.Printf("%04d-%02d:\n", m.Year, m.Month)
+	for _, day := range m.Days {
+		day.Print()
+	}
+	printPeriodSummary(m.Days, m.Config)
+}
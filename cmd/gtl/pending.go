@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+	"strings"
+)
+
+// readPending returns the title the last start left open, waiting to
+// be credited to whatever closes it, or "" if nothing is open. It
+// lives in its own small file since start and stop run as separate
+// processes and can't share state any other way.
+func readPending(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\n"), nil
+}
+
+// writePending persists title as the task now open.
+func writePending(path, title string) error {
+	return os.WriteFile(path, []byte(title+"\n"), 0644)
+}
+
+// clearPending removes whatever task was left open, leaving nothing
+// for the next start/stop to flush.
+func clearPending(path string) error {
+	err := os.Remove(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// sparkTicks are the block characters used to render a RangeReport's
+// daily-hours sparkline, from least to most worked.
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// RangeReport aggregates entries over an arbitrary date range, such as
+// --from=2024-03-01 --to=2024-03-15. Unlike WeekReport and MonthReport
+// it covers a single fixed period rather than a repeating one, so it
+// isn't driven through scanReports: Boundary just tells the caller
+// whether an entry falls outside [Start, End] and should be skipped,
+// and day-to-day transitions inside the range are handled by Append
+// alone.
+type RangeReport struct {
+	Start, End time.Time
+	Days       []*DayReport
+	Config     Config
+}
+
+// newRangeReport starts a RangeReport spanning the calendar dates
+// [start, end], both inclusive. start and end are truncated to their
+// own calendar date, so a time-of-day on either is ignored.
+func newRangeReport(start, end time.Time, config Config) *RangeReport {
+	return &RangeReport{Start: dateOnly(start), End: dateOnly(end), Config: config}
+}
+
+func (r *RangeReport) Append(dt time.Time, title string) {
+	r.Days = appendToDays(r.Days, r.Config, dt, title)
+}
+
+// Boundary reports whether dt's effective date falls outside [r.Start,
+// r.End], comparing calendar dates so the entire End date is included
+// regardless of what time of day dt carries.
+func (r *RangeReport) Boundary(dt time.Time) bool {
+	eff := dateOnly(r.Config.EffectiveDate(dt))
+	return eff.Before(r.Start) || eff.After(r.End)
+}
+
+// dateOnly truncates t to midnight on its own calendar date.
+func dateOnly(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// Print prints a per-week breakdown with overtime carried forward
+// cumulatively across weeks, followed by a sparkline of daily worked
+// hours across the whole range. Unlike WeekReport and MonthReport,
+// expected hours are computed from every calendar day in the range,
+// weekends and configured holidays excluded, even days with no entries
+// at all.
+func (r *RangeReport) Print() {
+	fmt.Printf("%s..%s:\n", r.Start.Format("2006-01-02"), r.End.Format("2006-01-02"))
+
+	byDate := make(map[string]*DayReport, len(r.Days))
+	for _, day := range r.Days {
+		byDate[day.Date.Format("2006-01-02")] = day
+	}
+
+	var hours []float64
+	var totalWorked, cumulative time.Duration
+	var weekYear, weekNum int
+	var weekWorked, weekExpected time.Duration
+	haveWeek := false
+
+	flushWeek := func() {
+		if !haveWeek {
+			return
+		}
+		cumulative += weekWorked - weekExpected
+		fmt.Printf("  %04d week %02d: worked %-10s expect %-10s cumulative %s\n",
+			weekYear, weekNum, weekWorked, weekExpected, cumulative)
+	}
+
+	for d := r.Start; !d.After(r.End); d = d.AddDate(0, 0, 1) {
+		year, week := r.Config.WeekNumber(d)
+		if !haveWeek || year != weekYear || week != weekNum {
+			flushWeek()
+			weekYear, weekNum = year, week
+			weekWorked, weekExpected = 0, 0
+			haveWeek = true
+		}
+
+		var worked time.Duration
+		if day, ok := byDate[d.Format("2006-01-02")]; ok {
+			worked = day.Worked()
+		}
+		hours = append(hours, worked.Hours())
+		totalWorked += worked
+		weekWorked += worked
+
+		if !r.Config.IsHoliday(d) && !isWeekend(d) {
+			weekExpected += r.Config.HoursPerDay
+		}
+	}
+	flushWeek()
+
+	fmt.Printf("  worked: %s\n", totalWorked)
+	fmt.Printf("overtime: %s\n", cumulative)
+	fmt.Printf("   daily: %s\n", sparkline(hours))
+}
+
+// sparkline renders hours as a single line of block characters scaled
+// between 0 and the largest value present.
+func sparkline(hours []float64) string {
+	max := 0.0
+	for _, h := range hours {
+		if h > max {
+			max = h
+		}
+	}
+
+	ticks := make([]rune, len(hours))
+	for i, h := range hours {
+		idx := 0
+		if max > 0 {
+			idx = int(h / max * float64(len(sparkTicks)-1))
+		}
+		ticks[i] = sparkTicks[idx]
+	}
+	return string(ticks)
+}
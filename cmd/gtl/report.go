@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type WeekReport struct {
+	Year   int
+	Week   int
+	Days   []*DayReport
+	Config Config
+}
+
+func (w *WeekReport) New(year, week int, start time.Time, config Config) {
+	w.Year = year
+	w.Week = week
+	w.Config = config
+	w.Days = []*DayReport{NewDayReport(start, config)}
+}
+
+func (w *WeekReport) Append(dt time.Time, title string) {
+	w.Days = appendToDays(w.Days, w.Config, dt, title)
+}
+
+// Boundary reports whether dt falls in a different ISO week than the
+// one w is currently aggregating.
+func (w *WeekReport) Boundary(dt time.Time) bool {
+	year, week := w.Config.WeekNumber(dt)
+	return year != w.Year || week != w.Week
+}
+
+func (w *WeekReport) Print() {
+	if len(w.Days) < 1 {
+		return
+	}
+	fmt.Printf("%04d week %02d:\n", w.Year, w.Week)
+	for _, day := range w.Days {
+		day.Print()
+	}
+	printPeriodSummary(w.Days, w.Config)
+}
+
+type DayReport struct {
+	Start time.Time
+	// Date is Start's effective calendar date (virtual midnight
+	// applied), used for display and for keying a day up by date. It
+	// can differ from Start's own literal date when Start falls between
+	// midnight and virtual midnight.
+	Date  time.Time
+	Spans []Span
+}
+
+func NewDayReport(start time.Time, cfg Config) *DayReport {
+	return &DayReport{Start: start, Date: dateOnly(cfg.EffectiveDate(start))}
+}
+
+func (d *DayReport) Print() {
+	fmt.Printf("  %s: %s\n", d.Date.Format("2006-01-02"), d.Worked())
+}
+
+func (d *DayReport) Worked() time.Duration {
+	var worked time.Duration
+	for _, iv := range d.Intervals() {
+		if iv.Work {
+			worked += iv.End.Sub(iv.Start)
+		}
+	}
+	return worked
+}
+
+// Interval is a single span expanded to carry its own start time, so
+// callers don't have to re-derive it by walking Spans themselves.
+type Interval struct {
+	Start time.Time
+	End   time.Time
+	Work  bool
+	Title string
+}
+
+// Intervals expands d's Spans into Intervals.
+func (d *DayReport) Intervals() []Interval {
+	intervals := make([]Interval, 0, len(d.Spans))
+	start := d.Start
+	for _, span := range d.Spans {
+		intervals = append(intervals, Interval{start, span.End, span.Work, span.Title})
+		start = span.End
+	}
+	return intervals
+}
+
+type Span struct {
+	End   time.Time
+	Work  bool
+	Title string
+}
+
+func isStarred(title string) bool {
+	_, found := strings.CutSuffix(title, "**")
+	return found
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Reporter aggregates a contiguous run of entries into a single
+// reporting period and prints a summary of it. WeekReport, MonthReport,
+// and RangeReport all implement it, so scanReports doesn't need to know
+// which kind of period it is building.
+type Reporter interface {
+	// Append adds dt/title to the period being built.
+	Append(dt time.Time, title string)
+	// Boundary reports whether dt falls outside the period Append has
+	// been building, meaning the caller should flush it and start a
+	// fresh one via newPeriod.
+	Boundary(dt time.Time) bool
+	Print()
+}
+
+// scanReports drives entries (as produced by scan) into a chain of
+// Reporters: whenever Boundary reports that the next entry falls
+// outside the current period, flush runs on it and newPeriod starts the
+// next one from that entry. flush also runs on the final period once
+// scan is exhausted.
+func scanReports(scan func(func(Entry) error) error, newPeriod func(dt time.Time) Reporter, flush func(Reporter)) error {
+	var current Reporter
+
+	err := scan(func(e Entry) error {
+		if current == nil || current.Boundary(e.When) {
+			if current != nil {
+				flush(current)
+			}
+			current = newPeriod(e.When)
+		} else {
+			current.Append(e.When, e.Title)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if current != nil {
+		flush(current)
+	}
+	return nil
+}
+
+// appendToDays is the day-splitting logic shared by WeekReport,
+// MonthReport, and RangeReport: dt/title extends the last DayReport in
+// days when dt's effective date matches it, otherwise it starts a new
+// one with dt as its arrival time, discarding title exactly like the
+// first entry of a fresh period does.
+func appendToDays(days []*DayReport, cfg Config, dt time.Time, title string) []*DayReport {
+	if len(days) == 0 {
+		return []*DayReport{NewDayReport(dt, cfg)}
+	}
+
+	last := days[len(days)-1]
+	if sameDay(cfg.EffectiveDate(last.Start), cfg.EffectiveDate(dt)) {
+		last.Spans = append(last.Spans, Span{dt, !isStarred(title), title})
+		return days
+	}
+	return append(days, NewDayReport(dt, cfg))
+}
+
+// printPeriodSummary prints the worked/expected/overtime footer shared
+// by WeekReport.Print and MonthReport.Print, once the caller has
+// already printed each day in days.
+func printPeriodSummary(days []*DayReport, cfg Config) {
+	var count int
+	var worked time.Duration
+	for _, day := range days {
+		if !cfg.IsHoliday(day.Date) {
+			count++
+		}
+		worked += day.Worked()
+	}
+
+	expected := cfg.HoursPerDay * time.Duration(count)
+	overtime := worked - expected
+	if count > 0 {
+		fmt.Printf("   daily: %s\n", worked/time.Duration(count))
+	}
+	fmt.Printf("  worked: %s\n", worked)
+	fmt.Printf("  expect: %s\n", expected)
+	if overtime > 0 {
+		fmt.Printf("    over: %s\n", overtime)
+	} else {
+		fmt.Printf("   under: %s\n", -overtime)
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"time"
+)
+
+// YYYY-MM-DD HH:MM: TITLE
+var entryRx = regexp.MustCompile(`(\d\d\d\d-\d\d-\d\d \d\d:\d\d): (.*)`)
+
+// Time format shared by the parser and the writer.
+const timeFormat = "2006-01-02 15:04"
+
+/*
+Here is a formal grammar:
+
+file ::= (entry|day-separator|comment|old-style-comment)*
+
+entry ::= timestamp ":" SPACE title NEWLINE
+
+day-separator ::= NEWLINE
+
+comment ::= "#" anything* NEWLINE
+
+old-style-comment ::= anything* NEWLINE
+
+title ::= anything*
+timestamp is YYYY-MM-DD HH:MM with a single space between the date and the time.
+
+anything is any character except a newline.
+
+NEWLINE is whatever Python considers it to be (i.e. CR LF or just LF).
+
+GTimeLog adds a blank line between days. It ignores them when loading, but this is likely to change in the future.
+
+GTimeLog considers any lines not starting with a valid timestamp to be comments. This is likely to change in the future, so please use '#' to indicate real comments if you find you need them.
+
+All lines should be sorted by time. Currently GTimeLog won't complain if they're not, and it will sort them to compensate.
+*/
+
+// Entry is a single parsed timestamp/title line from the timelog file.
+type Entry struct {
+	When  time.Time
+	Title string
+}
+
+// scanEntries reads r line by line and calls fn for each entry, in file
+// order. Lines that aren't valid entries (blank lines, comments) are
+// skipped.
+func scanEntries(r io.Reader, fn func(Entry) error) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		match := entryRx.FindStringSubmatch(line)
+		if len(match) == 0 {
+			continue
+		}
+
+		dt, err := parseEntryTime(match[1])
+		if err != nil {
+			return err
+		}
+
+		if err := fn(Entry{dt, match[2]}); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func parseEntryTime(s string) (time.Time, error) {
+	return time.Parse(timeFormat, s)
+}
+
+// lastEntry returns the last parsed entry in path, or the zero Entry if
+// the file has none.
+func lastEntry(path string) (Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Entry{}, err
+	}
+	defer f.Close()
+
+	var last Entry
+	err = scanEntries(f, func(e Entry) error {
+		last = e
+		return nil
+	})
+	return last, err
+}
+
+// appendEntry appends a new entry to path, inserting a blank day
+// separator when dt falls on a later day than the last entry. It
+// refuses to append an entry that is earlier than the last recorded
+// timestamp, since the file must stay sorted by time.
+func appendEntry(path string, dt time.Time, title string) error {
+	last, err := lastEntry(path)
+	if err != nil {
+		return err
+	}
+
+	if !last.When.IsZero() && dt.Before(last.When) {
+		return fmt.Errorf("refusing to append %s: earlier than last entry %s", dt.Format(timeFormat), last.When.Format(timeFormat))
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if !last.When.IsZero() && !sameDay(last.When, dt) {
+		if _, err := fmt.Fprintln(f); err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(f, "%s: %s\n", dt.Format(timeFormat), title)
+	return err
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
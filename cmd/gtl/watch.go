@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watch prints a live-updating WeekReport for path: it scans the file
+// once to build the current week, then watches for appends and parses
+// only the bytes that were added, without ever rescanning from the
+// start. An appended entry that crosses an ISO week boundary starts a
+// fresh WeekReport instead of carrying the old one forward.
+func watch(path string, cfg Config) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	var current WeekReport
+	err = scanEntries(f, func(e Entry) error {
+		year, week := cfg.WeekNumber(e.When)
+		if year == current.Year && week == current.Week {
+			current.Append(e.When, e.Title)
+		} else {
+			current.New(year, week, e.When, cfg)
+		}
+		return nil
+	})
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	offset, err := f.Seek(0, io.SeekCurrent)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	printRefreshed(&current)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(path); err != nil {
+		return err
+	}
+
+	var tailer lineTailer
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+
+			appended, err := readAppended(path, &offset)
+			if err != nil {
+				return err
+			}
+
+			err = tailer.feed(appended, func(e Entry) error {
+				year, week := cfg.WeekNumber(e.When)
+				if year == current.Year && week == current.Week {
+					current.Append(e.When, e.Title)
+				} else {
+					current = WeekReport{}
+					current.New(year, week, e.When, cfg)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			printRefreshed(&current)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// readAppended returns the bytes written to path since *offset, and
+// advances *offset past them.
+func readAppended(path string, offset *int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(*offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	*offset += int64(len(data))
+	return data, nil
+}
+
+// lineTailer buffers bytes fed to it until a full line is available, so
+// a write that lands mid-line doesn't get parsed as a (malformed)
+// entry.
+type lineTailer struct {
+	buf []byte
+}
+
+func (t *lineTailer) feed(data []byte, fn func(Entry) error) error {
+	t.buf = append(t.buf, data...)
+
+	for {
+		idx := bytes.IndexByte(t.buf, '\n')
+		if idx < 0 {
+			return nil
+		}
+
+		line := string(t.buf[:idx])
+		t.buf = t.buf[idx+1:]
+
+		match := entryRx.FindStringSubmatch(line)
+		if len(match) == 0 {
+			continue
+		}
+
+		dt, err := parseEntryTime(match[1])
+		if err != nil {
+			return err
+		}
+		if err := fn(Entry{dt, match[2]}); err != nil {
+			return err
+		}
+	}
+}
+
+// printRefreshed clears the screen and reprints w, for --watch mode.
+func printRefreshed(w *WeekReport) {
+	fmt.Print("\x1b[2J\x1b[H")
+	w.Print()
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ticketRx extracts a Jira-style issue key (e.g. PROJ-123) from a span
+// title.
+var ticketRx = regexp.MustCompile(`[A-Z]+-\d+`)
+
+// remainingComment is title with its first occurrence of key, and any
+// surrounding separator punctuation and whitespace, removed, leaving
+// just the descriptive text to post as the worklog comment.
+func remainingComment(title, key string) string {
+	rest := strings.Replace(title, key, "", 1)
+	return strings.Trim(rest, " \t:-,")
+}
+
+// WorklogConfig holds the credentials read from ~/.gtimelog/config,
+// stored there as base64("email:token;domain").
+type WorklogConfig struct {
+	Email  string
+	Token  string
+	Domain string
+}
+
+// loadWorklogConfig reads and decodes the config file at path.
+func loadWorklogConfig(path string) (*WorklogConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+
+	credentials, domain, found := strings.Cut(string(decoded), ";")
+	if !found {
+		return nil, fmt.Errorf("%s: expected \"email:token;domain\"", path)
+	}
+
+	email, token, found := strings.Cut(credentials, ":")
+	if !found {
+		return nil, fmt.Errorf("%s: expected \"email:token;domain\"", path)
+	}
+
+	return &WorklogConfig{Email: email, Token: token, Domain: domain}, nil
+}
+
+// WorklogClient posts worklog entries to a Jira/Tempo REST endpoint. With
+// DryRun set it prints the payloads instead of posting them.
+type WorklogClient struct {
+	Config *WorklogConfig
+	DryRun bool
+
+	httpClient *http.Client
+}
+
+func NewWorklogClient(cfg *WorklogConfig, dryRun bool) *WorklogClient {
+	return &WorklogClient{
+		Config:     cfg,
+		DryRun:     dryRun,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type worklogEntry struct {
+	IssueKey         string `json:"issueKey"`
+	Started          string `json:"started"`
+	TimeSpentSeconds int    `json:"timeSpentSeconds"`
+	Comment          string `json:"comment"`
+}
+
+// ExportWorklogs posts one worklog entry per non-starred span in w whose
+// title contains a ticket key. Spans without a recognizable key are
+// skipped.
+func (w *WeekReport) ExportWorklogs(client *WorklogClient) error {
+	for _, day := range w.Days {
+		start := day.Start
+		for _, span := range day.Spans {
+			if span.Work {
+				if key := ticketRx.FindString(span.Title); key != "" {
+					entry := worklogEntry{
+						IssueKey:         key,
+						Started:          start.Format(timeFormat),
+						TimeSpentSeconds: int(span.End.Sub(start).Seconds()),
+						Comment:          remainingComment(span.Title, key),
+					}
+					if err := client.post(entry); err != nil {
+						return err
+					}
+				}
+			}
+			start = span.End
+		}
+	}
+	return nil
+}
+
+// post submits entry, retrying with exponential backoff on 5xx
+// responses.
+func (c *WorklogClient) post(entry worklogEntry) error {
+	body, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if c.DryRun {
+		fmt.Println(string(body))
+		return nil
+	}
+
+	url := fmt.Sprintf("https://%s/rest/tempo-timesheets/4/worklogs", c.Config.Domain)
+
+	backoff := time.Second
+	for attempt := 0; attempt < 5; attempt++ {
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.SetBasicAuth(c.Config.Email, c.Config.Token)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode < http.StatusInternalServerError {
+			if resp.StatusCode >= 300 {
+				return fmt.Errorf("tempo: %s: unexpected status %s", entry.IssueKey, resp.Status)
+			}
+			return nil
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	return fmt.Errorf("tempo: %s: giving up after repeated 5xx responses", entry.IssueKey)
+}